@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/platform/query"
+)
+
+// encodeArrowIPC serializes a single Flux result table into an Arrow IPC
+// record batch, the wire format QueryFrame.arrow_ipc carries.
+func encodeArrowIPC(t query.Table) ([]byte, error) {
+	schema, err := arrowSchema(t.Cols())
+	if err != nil {
+		return nil, err
+	}
+
+	pool := memory.NewGoAllocator()
+	builders := make([]array.Builder, len(t.Cols()))
+	for i, c := range t.Cols() {
+		builders[i] = newArrowBuilder(pool, c.Type)
+	}
+
+	if err := t.Do(func(cr query.ColReader) error {
+		for i, c := range t.Cols() {
+			appendColumn(builders[i], cr, i, c.Type)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	arrays := make([]array.Interface, len(builders))
+	for i, b := range builders {
+		arrays[i] = b.NewArray()
+		defer arrays[i].Release()
+	}
+
+	record := array.NewRecord(schema, arrays, -1)
+	defer record.Release()
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	if err := w.Write(record); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func arrowSchema(cols []query.ColMeta) (*arrow.Schema, error) {
+	fields := make([]arrow.Field, len(cols))
+	for i, c := range cols {
+		dt, err := arrowType(c.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = arrow.Field{Name: c.Label, Type: dt}
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+func arrowType(t query.DataType) (arrow.DataType, error) {
+	switch t {
+	case query.TFloat:
+		return arrow.PrimitiveTypes.Float64, nil
+	case query.TInt:
+		return arrow.PrimitiveTypes.Int64, nil
+	case query.TUInt:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case query.TString:
+		return arrow.BinaryTypes.String, nil
+	case query.TBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case query.TTime:
+		return arrow.FixedWidthTypes.Timestamp_ns, nil
+	default:
+		return nil, fmt.Errorf("unsupported column type %v", t)
+	}
+}
+
+func newArrowBuilder(pool memory.Allocator, t query.DataType) array.Builder {
+	switch t {
+	case query.TFloat:
+		return array.NewFloat64Builder(pool)
+	case query.TInt:
+		return array.NewInt64Builder(pool)
+	case query.TUInt:
+		return array.NewUint64Builder(pool)
+	case query.TString:
+		return array.NewStringBuilder(pool)
+	case query.TBool:
+		return array.NewBooleanBuilder(pool)
+	case query.TTime:
+		return array.NewTimestampBuilder(pool, arrow.FixedWidthTypes.Timestamp_ns.(*arrow.TimestampType))
+	default:
+		return array.NewStringBuilder(pool)
+	}
+}
+
+func appendColumn(b array.Builder, cr query.ColReader, idx int, t query.DataType) {
+	switch t {
+	case query.TFloat:
+		b.(*array.Float64Builder).AppendValues(cr.Floats(idx), nil)
+	case query.TInt:
+		b.(*array.Int64Builder).AppendValues(cr.Ints(idx), nil)
+	case query.TUInt:
+		b.(*array.Uint64Builder).AppendValues(cr.UInts(idx), nil)
+	case query.TString:
+		b.(*array.StringBuilder).AppendValues(cr.Strings(idx), nil)
+	case query.TBool:
+		b.(*array.BooleanBuilder).AppendValues(cr.Bools(idx), nil)
+	case query.TTime:
+		tb := b.(*array.TimestampBuilder)
+		for _, v := range cr.Times(idx) {
+			tb.Append(arrow.Timestamp(v))
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/influxdata/platform"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type authKey struct{}
+
+var errUnauthenticated = status.Error(codes.Unauthenticated, "request context is missing an authorization")
+
+// authorizationFromContext returns the platform.Authorization resolved by
+// the auth interceptor for the current RPC.
+func authorizationFromContext(ctx context.Context) (*platform.Authorization, bool) {
+	auth, ok := ctx.Value(authKey{}).(*platform.Authorization)
+	return auth, ok
+}
+
+var errPermissionDenied = status.Error(codes.PermissionDenied, "authorization does not grant access to this resource")
+
+// authorizationAllows reports whether auth carries a permission granting
+// action on resourceType, scoped broadly enough to cover id. A permission
+// with a nil Resource.ID grants action on every resource of that type. id
+// may be nil when the caller has no specific resource to check against
+// (e.g. QueryService.Query, whose Flux query text can reference buckets
+// the RPC request itself doesn't name), in which case only an equally
+// unscoped permission satisfies the check, mirroring the HTTP handlers'
+// same all-or-nothing fallback for requests they can't scope precisely.
+func authorizationAllows(auth *platform.Authorization, action platform.Action, resourceType platform.ResourceType, id *platform.ID) bool {
+	for _, p := range auth.Permissions {
+		if p.Action != action || p.Resource.Type != resourceType {
+			continue
+		}
+		if p.Resource.ID == nil {
+			return true
+		}
+		if id != nil && *p.Resource.ID == *id {
+			return true
+		}
+	}
+	return false
+}
+
+func authenticate(ctx context.Context, svc platform.AuthorizationService) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx, status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+
+	token := strings.TrimPrefix(values[0], "Bearer ")
+
+	auth, err := svc.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return context.WithValue(ctx, authKey{}, auth), nil
+}
+
+// authUnaryInterceptor resolves the bearer token on unary RPCs through the
+// same AuthorizationService the HTTP handlers use.
+func authUnaryInterceptor(svc platform.AuthorizationService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context { return s.ctx }
+
+// authStreamInterceptor resolves the bearer token on streaming RPCs, e.g.
+// WriteService.Write, before handing off to the stream handler.
+func authStreamInterceptor(svc platform.AuthorizationService) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), svc)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,13 @@
+// Package pb holds the generated client/server bindings for the gRPC
+// services defined in service.proto.
+//
+// service.pb.go and service_grpc.pb.go are checked in as hand-maintained
+// stand-ins for protoc output: this environment does not have protoc or
+// the protoc-gen-go/protoc-gen-go-grpc plugins available to run the
+// generator. The go:generate directive below is the real regeneration
+// command; run it (after installing protoc and the two plugins) any time
+// service.proto changes, and check in the result instead of hand-editing
+// the generated files further.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative service.proto
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/grpc/pb"
+	"github.com/influxdata/platform/query"
+	"go.uber.org/zap"
+)
+
+type queryServer struct {
+	queryService query.QueryService
+	logger       *zap.Logger
+}
+
+// Query runs req through the same query.QueryService the HTTP Flux handler
+// uses, streaming back one QueryFrame per Arrow IPC record batch produced
+// by the result iterator.
+func (s *queryServer) Query(req *pb.QueryRequest, stream pb.QueryService_QueryServer) error {
+	ctx := stream.Context()
+
+	auth, ok := authorizationFromContext(ctx)
+	if !ok {
+		return errUnauthenticated
+	}
+
+	// The RPC only names an org, not a bucket; which buckets the Flux query
+	// text reads from isn't known until the query service parses it. Absent
+	// that, require an org-unscoped read permission rather than letting any
+	// authenticated token query any bucket in the org.
+	if !authorizationAllows(auth, platform.ReadAction, platform.BucketsResourceType, nil) {
+		return errPermissionDenied
+	}
+
+	results, err := s.queryService.Query(ctx, query.CompileRequest{
+		OrgID: req.OrgId,
+		Query: req.Query,
+	})
+	if err != nil {
+		return err
+	}
+	defer results.Release()
+
+	for results.More() {
+		result := results.Next()
+
+		tables := result.Tables()
+		if err := tables.Do(func(t query.Table) error {
+			ipc, err := encodeArrowIPC(t)
+			if err != nil {
+				return err
+			}
+
+			return stream.Send(&pb.QueryFrame{
+				Table:    t.Key().String(),
+				ArrowIpc: ipc,
+			})
+		}); err != nil {
+			s.logger.Info("query stream failed", zap.Error(err))
+			return err
+		}
+	}
+
+	return results.Err()
+}
@@ -0,0 +1,37 @@
+// Package grpc exposes the same write and query operations as the HTTP API
+// over gRPC, for clients that need high-throughput streaming ingest or
+// streaming query results. Both transports are backed by the same
+// APIBackend services, so authorization and business logic are identical.
+package grpc
+
+import (
+	"github.com/influxdata/platform/grpc/pb"
+	httpapi "github.com/influxdata/platform/http"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// NewGRPCServer constructs a *grpc.Server exposing WriteService and
+// QueryService, backed by the same services an APIHandler would use.
+func NewGRPCServer(b *httpapi.APIBackend) *grpc.Server {
+	logger := b.Logger.With(zap.String("transport", "grpc"))
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(authUnaryInterceptor(b.AuthorizationService)),
+		grpc.StreamInterceptor(authStreamInterceptor(b.AuthorizationService)),
+	)
+
+	pb.RegisterWriteServiceServer(srv, &writeServer{
+		publish:             b.PublisherFn,
+		organizationService: b.OrganizationService,
+		bucketService:       b.BucketService,
+		logger:              logger.With(zap.String("service", "write")),
+	})
+
+	pb.RegisterQueryServiceServer(srv, &queryServer{
+		queryService: b.QueryService,
+		logger:       logger.With(zap.String("service", "query")),
+	})
+
+	return srv
+}
@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/grpc/pb"
+	"go.uber.org/zap"
+)
+
+type writeServer struct {
+	publish             func(r io.Reader) error
+	organizationService platform.OrganizationService
+	bucketService       platform.BucketService
+	logger              *zap.Logger
+}
+
+// Write consumes a stream of WriteRequest chunks and publishes each chunk's
+// line protocol through the same PublisherFn the HTTP write path uses.
+func (s *writeServer) Write(stream pb.WriteService_WriteServer) error {
+	ctx := stream.Context()
+
+	auth, ok := authorizationFromContext(ctx)
+	if !ok {
+		return errUnauthenticated
+	}
+
+	var written int64
+	var validated bool
+	var orgID, bucketID, precision string
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.WriteSummary{PointsWritten: written})
+		}
+		if err != nil {
+			return err
+		}
+
+		// org_id/bucket_id/precision are constant for the life of the
+		// stream, so there is no need to re-resolve them on every chunk; a
+		// chunk that names a different destination is rejected.
+		if !validated {
+			if err := s.validateDestination(ctx, auth, req.OrgId, req.BucketId, req.Precision); err != nil {
+				s.logger.Info("write rejected", zap.Error(err))
+				return err
+			}
+			orgID, bucketID, precision = req.OrgId, req.BucketId, req.Precision
+			validated = true
+		} else if req.OrgId != orgID || req.BucketId != bucketID || req.Precision != precision {
+			err := fmt.Errorf("org_id, bucket_id and precision must not change within a stream")
+			s.logger.Info("write rejected", zap.Error(err))
+			return err
+		}
+
+		if err := s.publish(bytes.NewReader(req.LineProtocol)); err != nil {
+			s.logger.Info("write failed", zap.Error(err))
+			return err
+		}
+
+		written += countLines(req.LineProtocol)
+	}
+}
+
+// validWritePrecisions are the timestamp precisions InfluxDB line protocol
+// accepts; an empty precision defers to line protocol's nanosecond default.
+var validWritePrecisions = map[string]bool{
+	"":   true,
+	"ns": true,
+	"us": true,
+	"ms": true,
+	"s":  true,
+}
+
+// validateDestination checks that orgID and bucketID resolve to real
+// platform resources, that precision is a recognized line-protocol
+// timestamp precision, and that auth actually grants write access to the
+// resolved bucket, so the gRPC write path enforces the same validation and
+// authorization the HTTP write path does rather than trusting the caller's
+// IDs blindly or allowing any authenticated token to write anywhere.
+func (s *writeServer) validateDestination(ctx context.Context, auth *platform.Authorization, orgID, bucketID, precision string) error {
+	if !validWritePrecisions[precision] {
+		return fmt.Errorf("invalid precision %q", precision)
+	}
+
+	var org platform.ID
+	if err := org.DecodeFromString(orgID); err != nil {
+		return fmt.Errorf("invalid org_id %q: %v", orgID, err)
+	}
+	if _, err := s.organizationService.FindOrganizationByID(ctx, org); err != nil {
+		return fmt.Errorf("unknown org_id %q: %v", orgID, err)
+	}
+
+	var bucket platform.ID
+	if err := bucket.DecodeFromString(bucketID); err != nil {
+		return fmt.Errorf("invalid bucket_id %q: %v", bucketID, err)
+	}
+	if _, err := s.bucketService.FindBucketByID(ctx, bucket); err != nil {
+		return fmt.Errorf("unknown bucket_id %q: %v", bucketID, err)
+	}
+
+	if !authorizationAllows(auth, platform.WriteAction, platform.BucketsResourceType, &bucket) {
+		return errPermissionDenied
+	}
+
+	return nil
+}
+
+func countLines(lp []byte) int64 {
+	if len(lp) == 0 {
+		return 0
+	}
+	n := int64(bytes.Count(lp, []byte("\n")))
+	if lp[len(lp)-1] != '\n' {
+		n++
+	}
+	return n
+}
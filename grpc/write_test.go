@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/influxdata/platform"
+)
+
+func TestCountLines(t *testing.T) {
+	cases := []struct {
+		name string
+		lp   string
+		want int64
+	}{
+		{"empty", "", 0},
+		{"single line no trailing newline", "cpu value=1", 1},
+		{"single line with trailing newline", "cpu value=1\n", 1},
+		{"multiple lines", "cpu value=1\nmem value=2\n", 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := countLines([]byte(c.lp)); got != c.want {
+				t.Errorf("countLines(%q) = %d, want %d", c.lp, got, c.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizationAllowsScopedPermission(t *testing.T) {
+	bucket := platform.ID(1)
+	other := platform.ID(2)
+
+	auth := &platform.Authorization{Permissions: []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, ID: &bucket}},
+	}}
+
+	if !authorizationAllows(auth, platform.WriteAction, platform.BucketsResourceType, &bucket) {
+		t.Error("authorizationAllows() = false for a permission scoped to the exact bucket, want true")
+	}
+	if authorizationAllows(auth, platform.WriteAction, platform.BucketsResourceType, &other) {
+		t.Error("authorizationAllows() = true for a different bucket, want false")
+	}
+}
+
+func TestAuthorizationAllowsUnscopedPermission(t *testing.T) {
+	bucket := platform.ID(1)
+
+	auth := &platform.Authorization{Permissions: []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType}},
+	}}
+
+	if !authorizationAllows(auth, platform.WriteAction, platform.BucketsResourceType, &bucket) {
+		t.Error("authorizationAllows() = false for an org-unscoped permission, want true")
+	}
+}
+
+func TestAuthorizationAllowsRejectsWrongAction(t *testing.T) {
+	bucket := platform.ID(1)
+
+	auth := &platform.Authorization{Permissions: []platform.Permission{
+		{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType, ID: &bucket}},
+	}}
+
+	if authorizationAllows(auth, platform.WriteAction, platform.BucketsResourceType, &bucket) {
+		t.Error("authorizationAllows() = true for a read-only permission checked against write, want false")
+	}
+}
+
+func TestValidWritePrecisions(t *testing.T) {
+	for _, p := range []string{"", "ns", "us", "ms", "s"} {
+		if !validWritePrecisions[p] {
+			t.Errorf("validWritePrecisions[%q] = false, want true", p)
+		}
+	}
+	if validWritePrecisions["minutes"] {
+		t.Error(`validWritePrecisions["minutes"] = true, want false`)
+	}
+}
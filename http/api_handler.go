@@ -1,13 +1,17 @@
 package http
 
 import (
+	"context"
+	"fmt"
 	"io"
 	http "net/http"
 	"strings"
 
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/chronograf/server"
+	"github.com/influxdata/platform/http/middleware"
 	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/scraper"
 	pzap "github.com/influxdata/platform/zap"
 	"go.uber.org/zap"
 )
@@ -30,6 +34,13 @@ type APIHandler struct {
 	WriteHandler         *WriteHandler
 	SetupHandler         *SetupHandler
 	SessionHandler       *SessionHandler
+	ScraperHandler       *ScraperTargetHandler
+	AuditHandler         *AuditHandler
+	SourceProxyHandler   *SourceProxyHandler
+	NotifierTestHandler  *NotifierTestHandler
+
+	limiter *middleware.Limiter
+	handler http.Handler
 }
 
 // APIBackend is all services and associated parameters required to construct
@@ -57,7 +68,14 @@ type APIBackend struct {
 	QueryService               query.QueryService
 	TaskService                platform.TaskService
 	ScraperTargetStoreService  platform.ScraperTargetStoreService
+	ScraperService             *scraper.Service
 	ChronografService          *server.Service
+	KapacitorService           KapacitorService
+
+	RateLimits middleware.RateLimits
+
+	AuditLogger AuditLogger
+	AuditStore  AuditStore
 }
 
 // NewAPIHandler constructs all api handlers beneath it and returns an APIHandler
@@ -118,6 +136,34 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	h.ChronografHandler = NewChronografHandler(b.ChronografService)
 
+	h.ScraperHandler = NewScraperTargetHandler()
+	h.ScraperHandler.ScraperStorageService = b.ScraperTargetStoreService
+	h.ScraperHandler.ScraperService = scraperService(b)
+	h.ScraperHandler.Logger = b.Logger.With(zap.String("handler", "scraper"))
+
+	h.limiter = middleware.NewLimiter(b.RateLimits)
+
+	h.AuditHandler = NewAuditHandler()
+	h.AuditHandler.AuditStore = b.AuditStore
+	h.AuditHandler.AuthorizationService = b.AuthorizationService
+	h.AuditHandler.Logger = b.Logger.With(zap.String("handler", "audit"))
+
+	h.SourceProxyHandler = NewSourceProxyHandler()
+	h.SourceProxyHandler.SourceService = b.SourceService
+	h.SourceProxyHandler.NewBucketService = b.NewBucketService
+	h.SourceProxyHandler.NewQueryService = b.NewQueryService
+	h.SourceProxyHandler.Logger = b.Logger.With(zap.String("handler", "source_proxy"))
+
+	h.NotifierTestHandler = NewNotifierTestHandler()
+	h.NotifierTestHandler.KapacitorService = b.KapacitorService
+	h.NotifierTestHandler.Logger = b.Logger.With(zap.String("handler", "kapacitor_notifier"))
+
+	auditLogger := b.AuditLogger
+	if auditLogger == nil {
+		auditLogger = NewAuditLogger(b.Logger.With(zap.String("handler", "audit")), b.AuditStore)
+	}
+	h.handler = auditMiddleware(auditLogger, b.AuthorizationService, http.HandlerFunc(h.route))
+
 	return h
 }
 
@@ -134,6 +180,8 @@ var apiLinks = map[string]interface{}{
 	"buckets":    "/api/v2/buckets",
 	"users":      "/api/v2/users",
 	"tasks":      "/api/v2/tasks",
+	"scrapers":   "/api/v2/scrapers",
+	"audit":      "/api/v2/audit",
 	"flux": map[string]string{
 		"self":        "/api/v2/flux",
 		"ast":         "/api/v2/flux/ast",
@@ -149,6 +197,65 @@ var apiLinks = map[string]interface{}{
 	},
 }
 
+// scraperService returns the *scraper.Service to use for ScraperHandler. A
+// caller-provided b.ScraperService always wins; otherwise, if the backend
+// has enough to run one, a Service is built on top of b.PublisherFn via
+// scraperPublishFn so scraped targets actually get written somewhere
+// instead of the scraper silently never starting.
+func scraperService(b *APIBackend) *scraper.Service {
+	if b.ScraperService != nil {
+		return b.ScraperService
+	}
+	if b.ScraperTargetStoreService == nil || b.PublisherFn == nil {
+		return nil
+	}
+	return scraper.NewService(b.ScraperTargetStoreService, scraperPublishFn(b), b.Logger.With(zap.String("service", "scraper")))
+}
+
+// scraperPublishFn adapts b.PublisherFn, which always writes to a single
+// destination, into the per-target func(orgID, bucketID, r) error signature
+// scraper.Service.Publish expects. It validates that the target's org and
+// bucket still exist before delegating to PublisherFn, the same check the
+// gRPC write path applies to its destination IDs.
+func scraperPublishFn(b *APIBackend) func(orgID, bucketID platform.ID, r io.Reader) error {
+	return func(orgID, bucketID platform.ID, r io.Reader) error {
+		ctx := context.Background()
+
+		if _, err := b.OrganizationService.FindOrganizationByID(ctx, orgID); err != nil {
+			return fmt.Errorf("scraper: unknown org_id %q: %v", orgID, err)
+		}
+		if _, err := b.BucketService.FindBucketByID(ctx, bucketID); err != nil {
+			return fmt.Errorf("scraper: unknown bucket_id %q: %v", bucketID, err)
+		}
+
+		return b.PublisherFn(r)
+	}
+}
+
+// isSourceFederationPath reports whether path is one of the
+// /api/v2/sources/:id/{query,write,health} federation endpoints, as
+// opposed to the plain source CRUD routes handled by SourceHandler.
+func isSourceFederationPath(path string) bool {
+	if !strings.HasPrefix(path, "/api/v2/sources/") {
+		return false
+	}
+	return strings.HasSuffix(path, "/query") ||
+		strings.HasSuffix(path, "/write") ||
+		strings.HasSuffix(path, "/health")
+}
+
+// isKapacitorNotifierTestPath reports whether path is one of the
+// /chronograf/v1/kapacitors/:id/notifiers/test or
+// /chronograf/v1/kapacitors/:id/tasks/:tid/handlers endpoints served by
+// NotifierTestHandler, as opposed to the rest of the /chronograf/ prefix
+// handled by ChronografHandler.
+func isKapacitorNotifierTestPath(path string) bool {
+	if !strings.HasPrefix(path, "/chronograf/v1/kapacitors/") {
+		return false
+	}
+	return strings.HasSuffix(path, "/notifiers/test") || strings.HasSuffix(path, "/handlers")
+}
+
 func (h *APIHandler) serveLinks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	if err := encodeResponse(ctx, w, http.StatusOK, apiLinks); err != nil {
@@ -157,8 +264,14 @@ func (h *APIHandler) serveLinks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ServeHTTP delegates a request to the appropriate subhandler.
+// ServeHTTP delegates a request to the appropriate subhandler, wrapped in
+// the audit logging middleware configured in NewAPIHandler.
 func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// route dispatches a request to the appropriate subhandler.
+func (h *APIHandler) route(w http.ResponseWriter, r *http.Request) {
 	setCORSResponseHeaders(w, r)
 	if r.Method == "OPTIONS" {
 		return
@@ -181,57 +294,79 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/write") {
-		h.WriteHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteWrite, h.WriteHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
-		h.QueryHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteQuery, h.QueryHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets") {
-		h.BucketHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.BucketHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/users") {
-		h.UserHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.UserHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/orgs") {
-		h.OrgHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.OrgHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/authorizations") {
-		h.AuthorizationHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.AuthorizationHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/dashboards") {
-		h.DashboardHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.DashboardHandler).ServeHTTP(w, r)
+		return
+	}
+
+	if isSourceFederationPath(r.URL.Path) {
+		class := middleware.RouteGeneral
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/query"):
+			class = middleware.RouteQuery
+		case strings.HasSuffix(r.URL.Path, "/write"):
+			class = middleware.RouteWrite
+		}
+		h.limiter.Wrap(class, h.SourceProxyHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/sources") {
-		h.SourceHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.SourceHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/tasks") {
-		h.TaskHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.TaskHandler).ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/scrapers") {
+		h.limiter.Wrap(middleware.RouteGeneral, h.ScraperHandler).ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/audit") {
+		h.limiter.Wrap(middleware.RouteGeneral, h.AuditHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/views") {
-		h.ViewHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.ViewHandler).ServeHTTP(w, r)
 		return
 	}
 
 	if strings.HasPrefix(r.URL.Path, "/api/v2/macros") {
-		h.MacroHandler.ServeHTTP(w, r)
+		h.limiter.Wrap(middleware.RouteGeneral, h.MacroHandler).ServeHTTP(w, r)
 		return
 	}
 
@@ -240,6 +375,11 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isKapacitorNotifierTestPath(r.URL.Path) {
+		h.limiter.Wrap(middleware.RouteGeneral, h.NotifierTestHandler).ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.ChronografHandler.ServeHTTP(w, r)
 		return
@@ -0,0 +1,289 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform"
+	"go.uber.org/zap"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// maxAuditBodySummary bounds how much of a mutating request's redacted
+// body is kept in an audit event, so a large write doesn't end up
+// duplicated in the audit log.
+const maxAuditBodySummary = 1024
+
+// maxAuditBodyRead bounds how much of the request body is buffered for
+// redaction. It is larger than maxAuditBodySummary so that a sensitive
+// field near the end of a JSON body still gets redacted rather than
+// truncated away unredacted.
+const maxAuditBodyRead = 64 * 1024
+
+// redactedFieldNames are body field names (matched case-insensitively)
+// whose values are never written to the audit log, since the mutating
+// endpoints that accept them carry credentials: basic auth passwords,
+// notifier service keys, source/scraper auth, session tokens, etc.
+var redactedFieldNames = map[string]bool{
+	"password":      true,
+	"pass":          true,
+	"token":         true,
+	"secret":        true,
+	"apikey":        true,
+	"api_key":       true,
+	"servicekey":    true,
+	"service_key":   true,
+	"clientsecret":  true,
+	"client_secret": true,
+	"privatekey":    true,
+	"private_key":   true,
+	"authorization": true,
+	"credential":    true,
+	"credentials":   true,
+	"key":           true,
+	"accesstoken":   true,
+	"access_token":  true,
+	"refreshtoken":  true,
+	"refresh_token": true,
+}
+
+const redacted = "[redacted]"
+
+// AuditEvent is a single record of a mutating API call.
+type AuditEvent struct {
+	Time      time.Time   `json:"time"`
+	RequestID string      `json:"requestID"`
+	ActorID   platform.ID `json:"actorID"`
+	TokenID   platform.ID `json:"tokenID"`
+	OrgID     platform.ID `json:"orgID"`
+
+	ResourceType string      `json:"resourceType,omitempty"`
+	ResourceID   platform.ID `json:"resourceID,omitempty"`
+
+	Method   string        `json:"method"`
+	Status   int           `json:"status"`
+	Latency  time.Duration `json:"latency"`
+	BodySize int           `json:"bodySize"`
+	Body     string        `json:"body,omitempty"`
+}
+
+// AuditFilter narrows the events returned by AuditStore.FindAudits.
+type AuditFilter struct {
+	ActorID      *platform.ID
+	OrgID        *platform.ID
+	ResourceType string
+	Since        time.Time
+	Until        time.Time
+}
+
+// AuditStore persists and queries AuditEvents.
+type AuditStore interface {
+	WriteAudit(ctx context.Context, event AuditEvent) error
+	FindAudits(ctx context.Context, filter AuditFilter) ([]AuditEvent, error)
+}
+
+// AuditLogger records a single AuditEvent. Implementations must not block
+// the request they are auditing for any meaningful length of time.
+type AuditLogger interface {
+	Log(ctx context.Context, event AuditEvent)
+}
+
+// zapAuditLogger writes every event to both a zap.Logger (for operators
+// tailing service logs) and an AuditStore (for the /api/v2/audit query
+// endpoint).
+type zapAuditLogger struct {
+	logger *zap.Logger
+	store  AuditStore
+}
+
+// NewAuditLogger returns the default AuditLogger, which logs structured
+// JSON via logger and persists events to store.
+func NewAuditLogger(logger *zap.Logger, store AuditStore) AuditLogger {
+	return &zapAuditLogger{logger: logger, store: store}
+}
+
+func (a *zapAuditLogger) Log(ctx context.Context, event AuditEvent) {
+	a.logger.Info("audit",
+		zap.String("requestID", event.RequestID),
+		zap.String("actorID", event.ActorID.String()),
+		zap.String("orgID", event.OrgID.String()),
+		zap.String("resourceType", event.ResourceType),
+		zap.String("resourceID", event.ResourceID.String()),
+		zap.String("method", event.Method),
+		zap.Int("status", event.Status),
+		zap.Duration("latency", event.Latency),
+	)
+
+	if a.store == nil {
+		return
+	}
+	if err := a.store.WriteAudit(ctx, event); err != nil {
+		a.logger.Info("failed to persist audit event", zap.Error(err))
+	}
+}
+
+// fileAuditStore appends newline-delimited JSON audit events to a rotating
+// log file. It does not support querying; FindAudits always returns an
+// empty result, since paging back through rotated files is not yet
+// implemented.
+type fileAuditStore struct {
+	out *lumberjack.Logger
+}
+
+// NewFileAuditStore returns an AuditStore that appends events as JSON
+// lines to path, rotating the file per lumberjack's defaults overridden by
+// maxSizeMB and maxBackups.
+func NewFileAuditStore(path string, maxSizeMB, maxBackups int) AuditStore {
+	return &fileAuditStore{
+		out: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSizeMB,
+			MaxBackups: maxBackups,
+			Compress:   true,
+		},
+	}
+}
+
+func (f *fileAuditStore) WriteAudit(ctx context.Context, event AuditEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = f.out.Write(b)
+	return err
+}
+
+func (f *fileAuditStore) FindAudits(ctx context.Context, filter AuditFilter) ([]AuditEvent, error) {
+	return nil, nil
+}
+
+// auditMiddleware wraps next so that every request it serves other than GET
+// and OPTIONS is recorded as an AuditEvent once the response has been written.
+func auditMiddleware(logger AuditLogger, authSvc platform.AuthorizationService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+
+		var bodySummary string
+		var bodySize int
+		if r.Body != nil {
+			body, _ := ioutil.ReadAll(io.LimitReader(r.Body, maxAuditBodyRead))
+			bodySize = len(body)
+			r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+			bodySummary = summarizeBody(body)
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		event := AuditEvent{
+			Time:         start,
+			RequestID:    r.Header.Get("X-Request-Id"),
+			OrgID:        platform.InvalidID(),
+			ActorID:      platform.InvalidID(),
+			TokenID:      platform.InvalidID(),
+			ResourceType: resourceTypeFromPath(r.URL.Path),
+			Method:       r.Method,
+			Status:       rec.status,
+			Latency:      time.Since(start),
+			BodySize:     bodySize,
+			Body:         bodySummary,
+		}
+
+		if token := strings.TrimPrefix(r.Header.Get("Authorization"), "Token "); token != "" {
+			if auth, err := authSvc.FindAuthorizationByToken(r.Context(), token); err == nil {
+				event.ActorID = auth.UserID
+				event.TokenID = auth.ID
+				event.OrgID = auth.OrgID
+			}
+		}
+
+		logger.Log(r.Context(), event)
+	})
+}
+
+// summarizeBody redacts known-sensitive fields from body and truncates the
+// result to maxAuditBodySummary bytes. A body that isn't a JSON object or
+// array is not summarized at all, since we have no reliable way to find
+// and strip sensitive fields within it.
+func summarizeBody(body []byte) string {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return ""
+	}
+	if trimmed[0] != '{' && trimmed[0] != '[' {
+		return "[non-JSON body omitted]"
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(trimmed, &v); err != nil {
+		return "[unparseable body omitted]"
+	}
+
+	redactedJSON, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "[unparseable body omitted]"
+	}
+
+	if len(redactedJSON) > maxAuditBodySummary {
+		return string(redactedJSON[:maxAuditBodySummary]) + "...[truncated]"
+	}
+	return string(redactedJSON)
+}
+
+// redactValue walks a decoded JSON value, replacing the value of any
+// object key in redactedFieldNames with a fixed placeholder.
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			if redactedFieldNames[strings.ToLower(k)] {
+				out[k] = redacted
+				continue
+			}
+			out[k] = redactValue(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = redactValue(child)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+// resourceTypeFromPath pulls the resource kind out of an /api/v2/<kind>
+// path, e.g. "buckets" from "/api/v2/buckets/03a2b1".
+func resourceTypeFromPath(path string) string {
+	trimmed := strings.TrimPrefix(path, "/api/v2/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[0]
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
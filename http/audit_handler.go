@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform"
+	"go.uber.org/zap"
+)
+
+// AuditHandler serves GET /api/v2/audit, an admin-only endpoint for
+// querying recorded AuditEvents.
+type AuditHandler struct {
+	Logger *zap.Logger
+
+	AuditStore           AuditStore
+	AuthorizationService platform.AuthorizationService
+}
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{
+		Logger: zap.NewNop(),
+	}
+}
+
+func (h *AuditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+
+	if err := h.requireAdmin(ctx, r); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.AuditStore == nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInternal, Msg: "audit log is not configured"}, w)
+		return
+	}
+
+	filter, err := auditFilterFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Err: err}, w)
+		return
+	}
+
+	events, err := h.AuditStore.FindAudits(ctx, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, struct {
+		Events []AuditEvent `json:"events"`
+	}{Events: events}); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// requireAdmin rejects the request unless its bearer token resolves to an
+// authorization with write access to every organization, since the audit log can
+// contain other users' resource IDs and redacted-but-still-sensitive
+// request metadata across every organization.
+func (h *AuditHandler) requireAdmin(ctx context.Context, r *http.Request) error {
+	if h.AuthorizationService == nil {
+		return &platform.Error{Code: platform.EUnauthorized, Msg: "audit log is not available"}
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Token ")
+	if token == "" {
+		return &platform.Error{Code: platform.EUnauthorized, Msg: "authorization required"}
+	}
+
+	auth, err := h.AuthorizationService.FindAuthorizationByToken(ctx, token)
+	if err != nil {
+		return &platform.Error{Code: platform.EUnauthorized, Msg: "invalid token", Err: err}
+	}
+
+	if !isOperatorAuthorization(auth) {
+		return &platform.Error{Code: platform.EForbidden, Msg: "audit log requires an operator token"}
+	}
+
+	return nil
+}
+
+// isOperatorAuthorization reports whether auth carries org-unscoped write
+// access to organizations, the same bar used elsewhere for operator-only
+// actions. A token restricted to a single org cannot read the rest of the
+// fleet's audit trail.
+func isOperatorAuthorization(auth *platform.Authorization) bool {
+	for _, p := range auth.Permissions {
+		if p.Action == platform.WriteAction &&
+			p.Resource.Type == platform.OrgsResourceType &&
+			p.Resource.ID == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func auditFilterFromRequest(r *http.Request) (AuditFilter, error) {
+	q := r.URL.Query()
+
+	var filter AuditFilter
+	filter.ResourceType = q.Get("resource")
+
+	if actor := q.Get("actor"); actor != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(actor); err != nil {
+			return filter, err
+		}
+		filter.ActorID = &id
+	}
+
+	if org := q.Get("org"); org != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(org); err != nil {
+			return filter, err
+		}
+		filter.OrgID = &id
+	}
+
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return filter, err
+		}
+		filter.Since = t
+	}
+
+	if until := q.Get("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return filter, err
+		}
+		filter.Until = t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummarizeBodyRedactsSensitiveFields(t *testing.T) {
+	body := []byte(`{"username":"otis","password":"hunter2","nested":{"apiKey":"abc123"}}`)
+
+	got := summarizeBody(body)
+
+	if !strings.Contains(got, redacted) {
+		t.Fatalf("summarizeBody(%s) = %s, want it to contain %q", body, got, redacted)
+	}
+	if strings.Contains(got, "hunter2") {
+		t.Fatalf("summarizeBody(%s) = %s, leaked the password", body, got)
+	}
+	if strings.Contains(got, "abc123") {
+		t.Fatalf("summarizeBody(%s) = %s, leaked the nested apiKey", body, got)
+	}
+	if !strings.Contains(got, "otis") {
+		t.Fatalf("summarizeBody(%s) = %s, want non-sensitive fields preserved", body, got)
+	}
+}
+
+func TestSummarizeBodyNonJSON(t *testing.T) {
+	if got := summarizeBody([]byte("not json")); got != "[non-JSON body omitted]" {
+		t.Fatalf("summarizeBody(non-JSON) = %q, want the non-JSON placeholder", got)
+	}
+}
+
+func TestSummarizeBodyEmpty(t *testing.T) {
+	if got := summarizeBody(nil); got != "" {
+		t.Fatalf("summarizeBody(nil) = %q, want empty string", got)
+	}
+}
+
+func TestSummarizeBodyTruncates(t *testing.T) {
+	body := []byte(`{"data":"` + strings.Repeat("x", maxAuditBodySummary*2) + `"}`)
+
+	got := summarizeBody(body)
+
+	if len(got) > maxAuditBodySummary+len("...[truncated]") {
+		t.Fatalf("summarizeBody produced %d bytes, want it bounded near maxAuditBodySummary", len(got))
+	}
+	if !strings.Contains(got, "...[truncated]") {
+		t.Fatalf("summarizeBody(%d-byte body) = %q, want a truncation marker", len(body), got)
+	}
+}
+
+func TestRedactValueIsCaseInsensitive(t *testing.T) {
+	v := map[string]interface{}{"Password": "secret", "PASSWORD": "secret"}
+
+	out, ok := redactValue(v).(map[string]interface{})
+	if !ok {
+		t.Fatalf("redactValue returned %T, want map[string]interface{}", redactValue(v))
+	}
+	for k, val := range out {
+		if val != redacted {
+			t.Errorf("redactValue left %q unredacted: %v", k, val)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/kapacitor"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// KapacitorService resolves the kapacitor.Client configured for a given
+// source id, so that a single handler can test a notifier against whichever
+// kapacitor instance a UI has connected.
+type KapacitorService interface {
+	ClientFor(ctx context.Context, id platform.ID) (*kapacitor.Client, error)
+}
+
+// NotifierTestHandler serves the notifier-related endpoints nested under a
+// kapacitor source: firing a synthetic alert through a single notifier so
+// the UI can verify its configuration before saving it onto a rule, and
+// reading back the handlers already chained onto an existing task so an
+// edit form can be populated with what the task actually runs.
+type NotifierTestHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	KapacitorService KapacitorService
+}
+
+// NewNotifierTestHandler returns a new instance of NotifierTestHandler.
+func NewNotifierTestHandler() *NotifierTestHandler {
+	h := &NotifierTestHandler{
+		Router: httprouter.New(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("POST", "/chronograf/v1/kapacitors/:id/notifiers/test", h.handleTestNotifier)
+	h.HandlerFunc("GET", "/chronograf/v1/kapacitors/:id/tasks/:tid/handlers", h.handleGetTaskHandlers)
+
+	return h
+}
+
+func (h *NotifierTestHandler) handleTestNotifier(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var spec kapacitor.NotifierSpec
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Err: err}, w)
+		return
+	}
+
+	if h.KapacitorService == nil {
+		EncodeError(ctx, &platform.Error{Code: platform.ENotFound, Msg: "kapacitor is not configured"}, w)
+		return
+	}
+
+	client, err := h.KapacitorService.ClientFor(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := client.TestNotifier(ctx, spec); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInternal, Msg: "notifier test failed", Err: err}, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *NotifierTestHandler) handleGetTaskHandlers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	taskID := httprouter.ParamsFromContext(ctx).ByName("tid")
+	if taskID == "" {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: "url missing task id"}, w)
+		return
+	}
+
+	if h.KapacitorService == nil {
+		EncodeError(ctx, &platform.Error{Code: platform.ENotFound, Msg: "kapacitor is not configured"}, w)
+		return
+	}
+
+	client, err := h.KapacitorService.ClientFor(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	handlers, err := client.GetHandlers(ctx, taskID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, struct {
+		Handlers []kapacitor.NotifierSpec `json:"handlers"`
+	}{Handlers: handlers}); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
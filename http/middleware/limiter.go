@@ -0,0 +1,241 @@
+// Package middleware provides HTTP middleware shared across APIHandler's
+// subhandlers, starting with per-route rate limiting and concurrency
+// control.
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+// RouteClass groups routes that should share a rate limiting budget.
+type RouteClass string
+
+const (
+	// RouteWrite covers /api/v2/write.
+	RouteWrite RouteClass = "write"
+	// RouteQuery covers /api/v2/query.
+	RouteQuery RouteClass = "query"
+	// RouteGeneral covers everything else.
+	RouteGeneral RouteClass = "general"
+)
+
+// RateLimit configures a token-bucket limit for a single RouteClass.
+// Unlimited takes precedence over the numeric fields and is intended for
+// internal tokens that must never be throttled. A zero-value RateLimit
+// (RequestsPerSecond left unset) is also treated as unlimited: a limit of
+// zero requests per second isn't a meaningful throttle, it's the absence of
+// one, and an APIBackend that never populates RateLimits for a route class
+// must not have that class nearly blocked outright.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+	Unlimited         bool
+}
+
+// unlimited reports whether r should bypass the limiter entirely.
+func (r RateLimit) unlimited() bool {
+	return r.Unlimited || r.RequestsPerSecond <= 0
+}
+
+// RateLimits configures the limits applied to each route class, plus the
+// global cap on concurrently executing Flux queries.
+type RateLimits struct {
+	Write   RateLimit
+	Query   RateLimit
+	General RateLimit
+
+	// MaxConcurrentQueries bounds how many /api/v2/query requests may run
+	// at once. A saturated limiter returns 429 rather than queuing work
+	// onto QueryService. Zero means unlimited.
+	MaxConcurrentQueries int
+}
+
+var requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "platform",
+	Subsystem: "ratelimit",
+	Name:      "requests_total",
+	Help:      "Number of requests seen by the rate limiting middleware, partitioned by route class and outcome.",
+}, []string{"class", "result"})
+
+func init() {
+	prometheus.MustRegister(requestsTotal)
+}
+
+// bucketTTL is how long a bucket may sit unused before it is evicted. A
+// process that sees traffic from a steady trickle of distinct tokens/IPs
+// would otherwise grow l.buckets without bound for as long as it runs.
+const bucketTTL = 10 * time.Minute
+
+// bucketSweepInterval is how often idle buckets are swept for eviction.
+const bucketSweepInterval = time.Minute
+
+// bucketEntry pairs a token bucket with the last time it was used, so the
+// sweep loop can tell which buckets are safe to evict.
+type bucketEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter enforces RateLimits per (token, org, route class) and a global
+// concurrency cap on query routes.
+type Limiter struct {
+	limits RateLimits
+
+	mu      sync.Mutex
+	buckets map[string]*bucketEntry
+
+	querySem chan struct{}
+	stop     chan struct{}
+}
+
+// NewLimiter constructs a Limiter from limits. A zero MaxConcurrentQueries
+// disables the query concurrency cap. NewLimiter starts a background
+// goroutine that evicts idle buckets; call Close to stop it.
+func NewLimiter(limits RateLimits) *Limiter {
+	l := &Limiter{
+		limits:  limits,
+		buckets: map[string]*bucketEntry{},
+		stop:    make(chan struct{}),
+	}
+	if limits.MaxConcurrentQueries > 0 {
+		l.querySem = make(chan struct{}, limits.MaxConcurrentQueries)
+	}
+
+	go l.sweepLoop()
+
+	return l
+}
+
+// Close stops the background bucket eviction goroutine. It is safe, but not
+// required, to call during shutdown.
+func (l *Limiter) Close() {
+	close(l.stop)
+}
+
+func (l *Limiter) sweepLoop() {
+	ticker := time.NewTicker(bucketSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			l.sweep()
+		}
+	}
+}
+
+// sweep removes every bucket that has not been used within bucketTTL.
+func (l *Limiter) sweep() {
+	cutoff := time.Now().Add(-bucketTTL)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, b := range l.buckets {
+		if b.lastSeen.Before(cutoff) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Wrap returns next wrapped with rate limiting and, for RouteQuery,
+// concurrency limiting for the given class.
+func (l *Limiter) Wrap(class RouteClass, next http.Handler) http.Handler {
+	limit := l.limitFor(class)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := bucketKey(r, class)
+
+		if !limit.unlimited() && !l.allow(key, limit) {
+			requestsTotal.WithLabelValues(string(class), "rejected").Inc()
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		if class == RouteQuery && l.querySem != nil {
+			select {
+			case l.querySem <- struct{}{}:
+				defer func() { <-l.querySem }()
+			default:
+				requestsTotal.WithLabelValues(string(class), "rejected").Inc()
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many concurrent queries", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		requestsTotal.WithLabelValues(string(class), "accepted").Inc()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Limiter) limitFor(class RouteClass) RateLimit {
+	switch class {
+	case RouteWrite:
+		return l.limits.Write
+	case RouteQuery:
+		return l.limits.Query
+	default:
+		return l.limits.General
+	}
+}
+
+func (l *Limiter) allow(key string, limit RateLimit) bool {
+	l.mu.Lock()
+	b, ok := l.buckets[key]
+	if !ok {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		b = &bucketEntry{limiter: rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = time.Now()
+	l.mu.Unlock()
+
+	return b.limiter.Allow()
+}
+
+// bucketKey identifies the (token, org, class) tuple a request is limited
+// under. Requests without a bearer token fall back to the client's IP, so
+// that unauthenticated traffic from one host shares a single bucket across
+// connections rather than allocating a new one per TCP connection.
+func bucketKey(r *http.Request, class RouteClass) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Token ")
+	if token == "" {
+		token = "anon:" + remoteHost(r)
+	}
+	org := r.URL.Query().Get("org")
+	if org == "" {
+		org = r.URL.Query().Get("orgID")
+	}
+
+	return strings.Join([]string{token, org, string(class)}, "|")
+}
+
+// remoteHost returns the client IP from r.RemoteAddr with the ephemeral
+// port stripped off, falling back to the raw value if it isn't a host:port
+// pair.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RetryAfter formats d as a whole-second Retry-After header value.
+func RetryAfter(d time.Duration) string {
+	return strconv.Itoa(int(d / time.Second))
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBucketKeyUsesTokenWhenPresent(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+	r.Header.Set("Authorization", "Token abc123")
+	r.RemoteAddr = "10.0.0.1:4444"
+
+	if got, want := bucketKey(r, RouteGeneral), "abc123||general"; got != want {
+		t.Errorf("bucketKey() = %q, want %q", got, want)
+	}
+}
+
+func TestBucketKeySharesBucketAcrossConnectionsForSameHost(t *testing.T) {
+	r1 := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+	r1.RemoteAddr = "10.0.0.1:4444"
+
+	r2 := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+	r2.RemoteAddr = "10.0.0.1:5555"
+
+	k1 := bucketKey(r1, RouteGeneral)
+	k2 := bucketKey(r2, RouteGeneral)
+	if k1 != k2 {
+		t.Errorf("bucketKey() for the same host on different ports: %q != %q, want equal", k1, k2)
+	}
+}
+
+func TestBucketKeyDistinguishesHosts(t *testing.T) {
+	r1 := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+	r1.RemoteAddr = "10.0.0.1:4444"
+
+	r2 := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+	r2.RemoteAddr = "10.0.0.2:4444"
+
+	k1 := bucketKey(r1, RouteGeneral)
+	k2 := bucketKey(r2, RouteGeneral)
+	if k1 == k2 {
+		t.Errorf("bucketKey() for different hosts: both %q, want distinct", k1)
+	}
+}
+
+func TestRemoteHostFallsBackWithoutPort(t *testing.T) {
+	if got, want := remoteHost(&http.Request{RemoteAddr: "not-a-host-port"}), "not-a-host-port"; got != want {
+		t.Errorf("remoteHost() = %q, want %q", got, want)
+	}
+}
+
+func TestZeroValueRateLimitIsUnlimited(t *testing.T) {
+	var limit RateLimit
+	if !limit.unlimited() {
+		t.Error("RateLimit{}.unlimited() = false, want true")
+	}
+}
+
+func TestWrapAllowsTrafficForUnconfiguredRouteClass(t *testing.T) {
+	l := NewLimiter(RateLimits{})
+	defer l.Close()
+
+	handler := l.Wrap(RouteGeneral, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 5; i++ {
+		r := httptest.NewRequest("GET", "/api/v2/buckets", nil)
+		r.RemoteAddr = "10.0.0.1:4444"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d, want %d for an unconfigured route class", i, w.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestSweepEvictsOnlyIdleBuckets(t *testing.T) {
+	l := NewLimiter(RateLimits{General: RateLimit{RequestsPerSecond: 1, Burst: 1}})
+	defer l.Close()
+
+	l.mu.Lock()
+	l.buckets["stale"] = &bucketEntry{lastSeen: time.Now().Add(-2 * bucketTTL)}
+	l.buckets["fresh"] = &bucketEntry{lastSeen: time.Now()}
+	l.mu.Unlock()
+
+	l.sweep()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.buckets["stale"]; ok {
+		t.Error("sweep left a bucket idle for longer than bucketTTL in place")
+	}
+	if _, ok := l.buckets["fresh"]; !ok {
+		t.Error("sweep evicted a recently used bucket")
+	}
+}
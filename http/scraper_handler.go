@@ -0,0 +1,235 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/scraper"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// ScraperTargetHandler represents an HTTP API handler for scraper targets.
+type ScraperTargetHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	ScraperStorageService platform.ScraperTargetStoreService
+
+	// ScraperService, when set, is consulted for live scrape status. It is
+	// nil in deployments that only expose CRUD over targets.
+	ScraperService *scraper.Service
+}
+
+// NewScraperTargetHandler returns a new instance of ScraperTargetHandler.
+func NewScraperTargetHandler() *ScraperTargetHandler {
+	h := &ScraperTargetHandler{
+		Router: httprouter.New(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("POST", "/api/v2/scrapers", h.handlePostScraperTarget)
+	h.HandlerFunc("GET", "/api/v2/scrapers", h.handleGetScraperTargets)
+	h.HandlerFunc("GET", "/api/v2/scrapers/:id", h.handleGetScraperTarget)
+	h.HandlerFunc("PATCH", "/api/v2/scrapers/:id", h.handlePatchScraperTarget)
+	h.HandlerFunc("DELETE", "/api/v2/scrapers/:id", h.handleDeleteScraperTarget)
+	h.HandlerFunc("GET", "/api/v2/scrapers/:id/status", h.handleGetScraperTargetStatus)
+
+	return h
+}
+
+type scraperTargetLinks struct {
+	Self   string `json:"self"`
+	Status string `json:"status"`
+}
+
+type scraperTargetResponse struct {
+	platform.ScraperTarget
+	Links scraperTargetLinks `json:"links"`
+}
+
+func newScraperTargetResponse(t platform.ScraperTarget) scraperTargetResponse {
+	return scraperTargetResponse{
+		ScraperTarget: t,
+		Links: scraperTargetLinks{
+			Self:   "/api/v2/scrapers/" + t.ID.String(),
+			Status: "/api/v2/scrapers/" + t.ID.String() + "/status",
+		},
+	}
+}
+
+func (h *ScraperTargetHandler) handlePostScraperTarget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var target platform.ScraperTarget
+	if err := json.NewDecoder(r.Body).Decode(&target); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Err: err}, w)
+		return
+	}
+
+	if err := h.ScraperStorageService.AddTarget(ctx, &target); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.ScraperService != nil {
+		h.ScraperService.StartTarget(&target)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newScraperTargetResponse(target)); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+func (h *ScraperTargetHandler) handleGetScraperTargets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	targets, err := h.ScraperStorageService.FindScraperTargets(ctx, platform.ScraperTargetFilter{})
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	resp := make([]scraperTargetResponse, 0, len(targets))
+	for _, t := range targets {
+		resp = append(resp, newScraperTargetResponse(*t))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+func (h *ScraperTargetHandler) handleGetScraperTarget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	target, err := h.ScraperStorageService.GetTargetByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newScraperTargetResponse(*target)); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+func (h *ScraperTargetHandler) handlePatchScraperTarget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var update platform.ScraperTarget
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Err: err}, w)
+		return
+	}
+	update.ID = id
+
+	if err := h.ScraperStorageService.UpdateTarget(ctx, &update); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.ScraperService != nil {
+		// StartTarget cancels any loop already running for this ID before
+		// starting a new one, so the updated interval/URL/auth take effect
+		// immediately instead of waiting for the stale goroutine's closure
+		// to time out.
+		h.ScraperService.StartTarget(&update)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newScraperTargetResponse(update)); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+func (h *ScraperTargetHandler) handleDeleteScraperTarget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.ScraperService != nil {
+		h.ScraperService.StopTarget(id)
+	}
+
+	if err := h.ScraperStorageService.RemoveTarget(ctx, id); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type scraperTargetStatusResponse struct {
+	TargetID    platform.ID `json:"targetID"`
+	LastScrape  string      `json:"lastScrape,omitempty"`
+	LastError   string      `json:"lastError,omitempty"`
+	SampleCount int         `json:"sampleCount"`
+}
+
+func (h *ScraperTargetHandler) handleGetScraperTargetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if h.ScraperService == nil {
+		EncodeError(ctx, &platform.Error{Code: platform.ENotFound, Msg: "scraper service is not running"}, w)
+		return
+	}
+
+	status, ok := h.ScraperService.Status(id)
+	if !ok {
+		EncodeError(ctx, &platform.Error{Code: platform.ENotFound, Msg: "no status recorded for scraper target"}, w)
+		return
+	}
+
+	resp := scraperTargetStatusResponse{
+		TargetID:    status.TargetID,
+		LastError:   status.LastError,
+		SampleCount: status.SampleCount,
+	}
+	if !status.LastScrape.IsZero() {
+		resp.LastScrape = status.LastScrape.Format(timeFormat)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+const timeFormat = "2006-01-02T15:04:05.000000000Z"
+
+func idFromRequest(r *http.Request) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(r.Context())
+	id := params.ByName("id")
+	if id == "" {
+		return platform.InvalidID(), &platform.Error{Code: platform.EInvalid, Msg: "url missing id"}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return platform.InvalidID(), &platform.Error{Code: platform.EInvalid, Msg: "invalid id", Err: err}
+	}
+	return i, nil
+}
@@ -0,0 +1,397 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/query"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// consecutiveFailureThreshold is how many scrapes... er, queries/writes in
+// a row must fail against a source before SourceProxyHandler marks it
+// unhealthy and starts short-circuiting requests to it.
+const consecutiveFailureThreshold = 5
+
+// unhealthyCooldown is how long a tripped breaker waits before allowing a
+// single request through to probe whether the source has recovered.
+const unhealthyCooldown = 30 * time.Second
+
+// SourceProxyHandler federates /api/v2/query and /api/v2/write against a
+// remote platform.Source, resolved through SourceService, so a caller can
+// address any registered InfluxDB (v1 or v2) through the local API.
+type SourceProxyHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	SourceService    platform.SourceService
+	NewBucketService func(*platform.Source) (platform.BucketService, error)
+	NewQueryService  func(*platform.Source) (query.ProxyQueryService, error)
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// NewSourceProxyHandler returns a new instance of SourceProxyHandler.
+func NewSourceProxyHandler() *SourceProxyHandler {
+	h := &SourceProxyHandler{
+		Router:   httprouter.New(),
+		Logger:   zap.NewNop(),
+		breakers: map[string]*circuitBreaker{},
+	}
+
+	h.HandlerFunc("POST", "/api/v2/sources/:id/query", h.handleQuery)
+	h.HandlerFunc("POST", "/api/v2/sources/:id/write", h.handleWrite)
+	h.HandlerFunc("GET", "/api/v2/sources/:id/health", h.handleHealth)
+
+	return h
+}
+
+func (h *SourceProxyHandler) breaker(id platform.ID) *circuitBreaker {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b, ok := h.breakers[id.String()]
+	if !ok {
+		b = &circuitBreaker{}
+		h.breakers[id.String()] = b
+	}
+	return b
+}
+
+func (h *SourceProxyHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	source, err := h.SourceService.FindSourceByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	breaker := h.breaker(id)
+	if !breaker.allow() {
+		EncodeError(ctx, &platform.Error{Code: platform.EUnavailable, Msg: fmt.Sprintf("source %s is unhealthy", id)}, w)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		breaker.recordFailure()
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Err: err}, w)
+		return
+	}
+
+	fluxQuery := string(body)
+	if isV1Source(source) {
+		translated, err := translateV1Query(fluxQuery)
+		if err != nil {
+			EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: "cannot bridge query to v1 source", Err: err}, w)
+			return
+		}
+		fluxQuery = translated
+	}
+
+	proxyQueryService, err := h.NewQueryService(source)
+	if err != nil {
+		breaker.recordFailure()
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req := query.ProxyRequest{
+		Request: query.Request{
+			Compiler: query.FluxCompiler{Query: fluxQuery},
+		},
+		Dialect: query.CSVDialect{},
+	}
+
+	if _, err := proxyQueryService.Query(ctx, w, &req); err != nil {
+		breaker.recordFailure()
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	breaker.recordSuccess()
+}
+
+func (h *SourceProxyHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	source, err := h.SourceService.FindSourceByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	breaker := h.breaker(id)
+	if !breaker.allow() {
+		EncodeError(ctx, &platform.Error{Code: platform.EUnavailable, Msg: fmt.Sprintf("source %s is unhealthy", id)}, w)
+		return
+	}
+
+	dest, err := writeDestinationFromRequest(r, source)
+	if err != nil {
+		breaker.recordFailure()
+		EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Err: err}, w)
+		return
+	}
+
+	if dest.Bucket != "" {
+		bucketService, err := h.NewBucketService(source)
+		if err != nil {
+			breaker.recordFailure()
+			EncodeError(ctx, err, w)
+			return
+		}
+		if _, err := bucketService.FindBucket(ctx, platform.BucketFilter{
+			Organization: &dest.Org,
+			Name:         &dest.Bucket,
+		}); err != nil {
+			breaker.recordFailure()
+			EncodeError(ctx, &platform.Error{Code: platform.EInvalid, Msg: fmt.Sprintf("unknown bucket %q in org %q on source %s", dest.Bucket, dest.Org, id), Err: err}, w)
+			return
+		}
+	}
+
+	if err := writeToSource(ctx, source, dest, r.Body); err != nil {
+		breaker.recordFailure()
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	breaker.recordSuccess()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeDestination identifies where on a remote source a write should
+// land: org/bucket for a v2 source, or database/retention policy for a v1
+// source.
+type writeDestination struct {
+	Org    string
+	Bucket string
+
+	Database        string
+	RetentionPolicy string
+}
+
+// writeDestinationFromRequest reads the org/bucket or db/rp query
+// parameters the caller used to address the remote source, mirroring the
+// parameters the remote's own /write endpoint expects.
+func writeDestinationFromRequest(r *http.Request, source *platform.Source) (writeDestination, error) {
+	q := r.URL.Query()
+
+	if isV1Source(source) {
+		db := q.Get("db")
+		if db == "" {
+			return writeDestination{}, fmt.Errorf("db query parameter is required to write to a v1 source")
+		}
+		return writeDestination{Database: db, RetentionPolicy: q.Get("rp")}, nil
+	}
+
+	org := q.Get("org")
+	bucket := q.Get("bucket")
+	if org == "" || bucket == "" {
+		return writeDestination{}, fmt.Errorf("org and bucket query parameters are required to write to a v2 source")
+	}
+	return writeDestination{Org: org, Bucket: bucket}, nil
+}
+
+type sourceHealthResponse struct {
+	SourceID platform.ID `json:"sourceID"`
+	Healthy  bool        `json:"healthy"`
+	Failures int         `json:"consecutiveFailures"`
+}
+
+func (h *SourceProxyHandler) handleHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := idFromRequest(r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	breaker := h.breaker(id)
+	resp := sourceHealthResponse{
+		SourceID: id,
+		Healthy:  breaker.failures() < consecutiveFailureThreshold,
+		Failures: breaker.failures(),
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		h.Logger.Error("failed to encode response", zap.Error(err))
+	}
+}
+
+// writeToSource forwards line protocol to a remote source's write
+// endpoint, translating the caller's request into the source's own stored
+// credentials rather than passing the caller's token through, and
+// addressing dest using whichever query parameters the remote's API
+// version expects.
+func writeToSource(ctx context.Context, source *platform.Source, dest writeDestination, body io.Reader) error {
+	addr := strings.TrimSuffix(source.URL, "/") + "/write?" + destinationQueryString(dest)
+
+	req, err := http.NewRequest("POST", addr, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	if source.Username != "" {
+		req.SetBasicAuth(source.Username, source.Password)
+	} else if source.Token != "" {
+		req.Header.Set("Authorization", "Token "+source.Token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remote write to source %s failed with status %d", source.ID, resp.StatusCode)
+	}
+	return nil
+}
+
+// destinationQueryString renders dest as the query string the remote
+// source's own /write endpoint expects: org/bucket for v2, db/rp for v1.
+func destinationQueryString(dest writeDestination) string {
+	v := url.Values{}
+	if dest.Bucket != "" {
+		v.Set("org", dest.Org)
+		v.Set("bucket", dest.Bucket)
+	} else {
+		v.Set("db", dest.Database)
+		if dest.RetentionPolicy != "" {
+			v.Set("rp", dest.RetentionPolicy)
+		}
+	}
+	return v.Encode()
+}
+
+func isV1Source(source *platform.Source) bool {
+	return source.Type == platform.SourceTypeV1
+}
+
+// fluxFromBucket matches `from(bucket:"db/rp")` or `from(bucket:"db")` so a
+// Flux query can be bridged onto a v1 SELECT/SHOW-based source.
+var fluxFromBucket = regexp.MustCompile(`from\(bucket:\s*"([^"/]+)(?:/([^"]*))?"\)`)
+
+// fluxMeasurementEquals matches a `filter(fn: (r) => r._measurement ==
+// "cpu")` stage, the only predicate this bridge understands.
+var fluxMeasurementEquals = regexp.MustCompile(`r\._measurement\s*==\s*"([^"]+)"`)
+
+// fluxRange matches a `range(start: -1h)` or `range(start: -1h, stop:
+// -30m)` call using relative Flux durations, the only form of range this
+// bridge understands.
+var fluxRange = regexp.MustCompile(`range\(start:\s*-(\d+[a-zµu]+)\s*(?:,\s*stop:\s*-(\d+[a-zµu]+))?\)`)
+
+// translateV1Query rewrites a narrow shape of Flux query -
+// from(bucket:"db/rp") |> range(start: -1h) |> filter(fn: (r) =>
+// r._measurement == "m") - into the InfluxQL a v1 source can actually
+// execute, since a v1 source has no Flux support at all. Anything outside
+// that shape is rejected with a clear error rather than silently
+// mistranslated or forwarded as Flux a v1 source will just reject.
+func translateV1Query(q string) (string, error) {
+	fromMatches := fluxFromBucket.FindAllStringSubmatch(q, -1)
+	if len(fromMatches) != 1 {
+		return "", fmt.Errorf("query must contain exactly one from(bucket:) call to bridge to a v1 source")
+	}
+	database := fromMatches[0][1]
+	retentionPolicy := fromMatches[0][2]
+
+	measurementMatches := fluxMeasurementEquals.FindAllStringSubmatch(q, -1)
+	if len(measurementMatches) != 1 {
+		return "", fmt.Errorf(`query must filter on exactly one r._measurement == "..." to bridge to a v1 source`)
+	}
+	measurement := measurementMatches[0][1]
+
+	from := fmt.Sprintf(`"%s"."%s"."%s"`, database, retentionPolicy, measurement)
+	if retentionPolicy == "" {
+		from = fmt.Sprintf(`"%s".."%s"`, database, measurement)
+	}
+
+	influxql := "SELECT * FROM " + from
+	if rangeMatch := fluxRange.FindStringSubmatch(q); rangeMatch != nil {
+		influxql += fmt.Sprintf(" WHERE time >= now() - %s", rangeMatch[1])
+		if rangeMatch[2] != "" {
+			influxql += fmt.Sprintf(" AND time <= now() - %s", rangeMatch[2])
+		}
+	}
+
+	return influxql, nil
+}
+
+// circuitBreaker trips after consecutiveFailureThreshold failures in a
+// row and stays tripped for unhealthyCooldown before allowing a single
+// probe request through. While that probe is in flight, allow rejects
+// every other request rather than letting the full concurrent volume
+// through, so a genuinely down source isn't hammered while it recovers.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	consecutive int
+	trippedAt   time.Time
+	probing     bool
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.consecutive < consecutiveFailureThreshold {
+		return true
+	}
+	if b.probing || time.Since(b.trippedAt) <= unhealthyCooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutive = 0
+	b.probing = false
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutive++
+	b.probing = false
+	if b.consecutive >= consecutiveFailureThreshold {
+		b.trippedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) failures() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consecutive
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranslateV1QuerySimpleBridge(t *testing.T) {
+	q := `from(bucket:"telegraf/autogen") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "cpu")`
+
+	got, err := translateV1Query(q)
+	if err != nil {
+		t.Fatalf("translateV1Query: unexpected error: %v", err)
+	}
+
+	want := `SELECT * FROM "telegraf"."autogen"."cpu" WHERE time >= now() - 1h`
+	if got != want {
+		t.Errorf("translateV1Query() = %q, want %q", got, want)
+	}
+}
+
+func TestTranslateV1QueryNoRetentionPolicy(t *testing.T) {
+	q := `from(bucket:"telegraf") |> filter(fn: (r) => r._measurement == "cpu")`
+
+	got, err := translateV1Query(q)
+	if err != nil {
+		t.Fatalf("translateV1Query: unexpected error: %v", err)
+	}
+	if !strings.Contains(got, `"telegraf".."cpu"`) {
+		t.Errorf("translateV1Query() = %q, want it to reference the bucket without a retention policy", got)
+	}
+}
+
+func TestTranslateV1QueryRejectsMultipleFrom(t *testing.T) {
+	q := `from(bucket:"a") |> filter(fn: (r) => r._measurement == "cpu")
+union(tables: [from(bucket:"b")])`
+
+	if _, err := translateV1Query(q); err == nil {
+		t.Fatal("translateV1Query with two from(bucket:) calls: got nil error, want one")
+	}
+}
+
+func TestTranslateV1QueryRejectsMissingMeasurementFilter(t *testing.T) {
+	q := `from(bucket:"telegraf/autogen") |> range(start: -1h)`
+
+	if _, err := translateV1Query(q); err == nil {
+		t.Fatal("translateV1Query without a measurement filter: got nil error, want one")
+	}
+}
+
+func TestCircuitBreakerAllowsOnlyOneProbeAfterCooldown(t *testing.T) {
+	b := &circuitBreaker{}
+	for i := 0; i < consecutiveFailureThreshold; i++ {
+		b.recordFailure()
+	}
+	if b.allow() {
+		t.Fatal("allow() = true immediately after tripping, want false")
+	}
+
+	b.trippedAt = time.Now().Add(-unhealthyCooldown - time.Second)
+	if !b.allow() {
+		t.Fatal("allow() = false for the first request after cooldown, want true for the probe")
+	}
+	if b.allow() {
+		t.Fatal("allow() = true for a second concurrent request while the probe is in flight, want false")
+	}
+}
+
+func TestCircuitBreakerReopensCooldownWhenProbeFails(t *testing.T) {
+	b := &circuitBreaker{}
+	for i := 0; i < consecutiveFailureThreshold; i++ {
+		b.recordFailure()
+	}
+	b.trippedAt = time.Now().Add(-unhealthyCooldown - time.Second)
+
+	if !b.allow() {
+		t.Fatal("allow() = false for the probe request, want true")
+	}
+	b.recordFailure()
+
+	if b.allow() {
+		t.Fatal("allow() = true right after a failed probe, want false until another full cooldown elapses")
+	}
+}
+
+func TestDestinationQueryStringV2(t *testing.T) {
+	got := destinationQueryString(writeDestination{Org: "my-org", Bucket: "my-bucket"})
+	if got != "bucket=my-bucket&org=my-org" {
+		t.Errorf("destinationQueryString(v2) = %q, want %q", got, "bucket=my-bucket&org=my-org")
+	}
+}
+
+func TestDestinationQueryStringV1(t *testing.T) {
+	got := destinationQueryString(writeDestination{Database: "telegraf", RetentionPolicy: "autogen"})
+	if got != "db=telegraf&rp=autogen" {
+		t.Errorf("destinationQueryString(v1) = %q, want %q", got, "db=telegraf&rp=autogen")
+	}
+}
+
+func TestDestinationQueryStringV1NoRetentionPolicy(t *testing.T) {
+	got := destinationQueryString(writeDestination{Database: "telegraf"})
+	if got != "db=telegraf" {
+		t.Errorf("destinationQueryString(v1, no rp) = %q, want %q", got, "db=telegraf")
+	}
+}
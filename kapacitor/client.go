@@ -3,6 +3,9 @@ package kapacitor
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/influxdata/chronograf"
 	client "github.com/influxdata/kapacitor/client/v1"
@@ -23,12 +26,20 @@ const (
 )
 
 // Task represents a running kapacitor task
+//
+// Handlers lives here rather than on chronograf.AlertRule because AlertRule
+// is defined in the external chronograf package, which this change does not
+// touch; Task is the boundary this package owns, so it is where the
+// generated/parsed notifier configuration for a given TICKscript is tracked.
+// GetHandlers lets a caller recover a task's configured Handlers directly
+// from Kapacitor by ID without needing to have kept a Task value around.
 type Task struct {
 	ID         string                // Kapacitor ID
 	Href       string                // Kapacitor relative URI
 	HrefOutput string                // Kapacitor relative URI to HTTPOutNode
 	Rule       chronograf.AlertRule  // Rule is the rule that represents this Task
 	TICKScript chronograf.TICKScript // TICKScript is the running script
+	Handlers   []NotifierSpec        // Handlers is the set of notifiers chained onto TICKScript
 }
 
 // Href returns the link to a kapacitor task given an id
@@ -41,8 +52,10 @@ func (c *Client) HrefOutput(ID string) string {
 	return fmt.Sprintf("/kapacitor/v1/tasks/%s/%s", ID, HTTPEndpoint)
 }
 
-// Create builds and POSTs a tickscript to kapacitor
-func (c *Client) Create(ctx context.Context, rule chronograf.AlertRule) (*Task, error) {
+// Create builds and POSTs a tickscript to kapacitor. handlers is appended
+// to the generated TICKscript as one pipeline node per configured
+// notifier, so the returned Task alerts through all of them.
+func (c *Client) Create(ctx context.Context, rule chronograf.AlertRule, handlers []NotifierSpec) (*Task, error) {
 	kapa, err := c.kapaClient(ctx)
 	if err != nil {
 		return nil, err
@@ -58,6 +71,11 @@ func (c *Client) Create(ctx context.Context, rule chronograf.AlertRule) (*Task,
 		return nil, err
 	}
 
+	script, err = appendHandlers(script, handlers)
+	if err != nil {
+		return nil, err
+	}
+
 	kapaID := Prefix + id
 	rule.ID = kapaID
 	task, err := kapa.CreateTask(client.CreateTaskOptions{
@@ -77,9 +95,24 @@ func (c *Client) Create(ctx context.Context, rule chronograf.AlertRule) (*Task,
 		HrefOutput: c.HrefOutput(kapaID),
 		TICKScript: script,
 		Rule:       rule,
+		Handlers:   handlers,
 	}, nil
 }
 
+// appendHandlers generates the TICKscript nodes for handlers and appends
+// them to script.
+func appendHandlers(script chronograf.TICKScript, handlers []NotifierSpec) (chronograf.TICKScript, error) {
+	if len(handlers) == 0 {
+		return script, nil
+	}
+
+	nodes, err := GenerateHandlers(handlers)
+	if err != nil {
+		return "", err
+	}
+	return script + chronograf.TICKScript(nodes), nil
+}
+
 // Delete removes tickscript task from kapacitor
 func (c *Client) Delete(ctx context.Context, href string) error {
 	kapa, err := c.kapaClient(ctx)
@@ -218,8 +251,9 @@ func (c *Client) Get(ctx context.Context, id string) (chronograf.AlertRule, erro
 	return rule, nil
 }
 
-// Update changes the tickscript of a given id.
-func (c *Client) Update(ctx context.Context, href string, rule chronograf.AlertRule) (*Task, error) {
+// Update changes the tickscript of a given id. handlers replaces whatever
+// notifiers were previously chained onto the task, the same as Create.
+func (c *Client) Update(ctx context.Context, href string, rule chronograf.AlertRule, handlers []NotifierSpec) (*Task, error) {
 	kapa, err := c.kapaClient(ctx)
 	if err != nil {
 		return nil, err
@@ -230,6 +264,11 @@ func (c *Client) Update(ctx context.Context, href string, rule chronograf.AlertR
 		return nil, err
 	}
 
+	script, err = appendHandlers(script, handlers)
+	if err != nil {
+		return nil, err
+	}
+
 	// We need to disable the kapacitor task followed by enabling it during update.
 	opts := client.UpdateTaskOptions{
 		TICKscript: string(script),
@@ -259,9 +298,28 @@ func (c *Client) Update(ctx context.Context, href string, rule chronograf.AlertR
 		HrefOutput: c.HrefOutput(task.ID),
 		TICKScript: script,
 		Rule:       rule,
+		Handlers:   handlers,
 	}, nil
 }
 
+// GetHandlers returns the notifiers chained onto the task at id, recovered
+// from its live TICKscript via ParseHandlers. Callers use this to populate
+// an edit form with the handlers a task was actually created with, rather
+// than trusting a value that may have drifted out of band.
+func (c *Client) GetHandlers(ctx context.Context, id string) ([]NotifierSpec, error) {
+	kapa, err := c.kapaClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := kapa.Task(client.Link{Href: c.Href(id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseHandlers(task.TICKscript), nil
+}
+
 func (c *Client) kapaClient(ctx context.Context) (*client.Client, error) {
 	var creds *client.Credentials
 	if c.Username != "" {
@@ -284,3 +342,91 @@ func toTask(q chronograf.QueryConfig) client.TaskType {
 	}
 	return client.BatchTask
 }
+
+const (
+	testNotifierDatabase        = "_internal"
+	testNotifierRetentionPolicy = "monitor"
+	testNotifierMeasurement     = "chronograf-test"
+
+	// testNotifierPropagationDelay gives the stream task time to consume
+	// the point written below and fire the alert before the task is torn
+	// down; without it the task is deleted before kapacitor has a chance
+	// to run it through the notifier node.
+	testNotifierPropagationDelay = 2 * time.Second
+)
+
+// TestNotifier fires a synthetic alert through a single notifier, bypassing
+// any configured rule, so that the UI can verify a handler's credentials
+// before saving it on a task. It creates a one-off stream task wired to
+// the requested notifier, writes a single point so the task actually has
+// something to alert on, waits for it to propagate, and then removes the
+// task regardless of outcome.
+func (c *Client) TestNotifier(ctx context.Context, spec NotifierSpec) error {
+	kapa, err := c.kapaClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	handler, err := GenerateHandlers([]NotifierSpec{spec})
+	if err != nil {
+		return err
+	}
+
+	id := Prefix + "test-" + string(spec.Type)
+	script := fmt.Sprintf("stream\n    |from()\n        .measurement('%s')%s", testNotifierMeasurement, handler)
+
+	task, err := kapa.CreateTask(client.CreateTaskOptions{
+		ID:         id,
+		Type:       client.StreamTask,
+		DBRPs:      []client.DBRP{{Database: testNotifierDatabase, RetentionPolicy: testNotifierRetentionPolicy}},
+		TICKscript: script,
+		Status:     client.Enabled,
+	})
+	if err != nil {
+		return err
+	}
+	defer kapa.DeleteTask(task.Link)
+
+	if err := c.writeTestPoint(ctx); err != nil {
+		return fmt.Errorf("writing synthetic alert point: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(testNotifierPropagationDelay):
+	}
+
+	return nil
+}
+
+// writeTestPoint posts a single line-protocol point to kapacitor's
+// InfluxDB-compatible write endpoint, into the database/retention policy
+// the test task above is subscribed to, so the stream task has a sample
+// to run through the notifier node.
+func (c *Client) writeTestPoint(ctx context.Context) error {
+	line := fmt.Sprintf("%s value=1 %d\n", testNotifierMeasurement, time.Now().UnixNano())
+
+	url := strings.TrimSuffix(c.URL, "/") +
+		"/write?db=" + testNotifierDatabase + "&rp=" + testNotifierRetentionPolicy
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(line))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("kapacitor write endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
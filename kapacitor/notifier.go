@@ -0,0 +1,205 @@
+package kapacitor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotifierType identifies which alert handler a NotifierSpec configures.
+type NotifierType string
+
+const (
+	// Slack posts alerts to a Slack channel via an incoming webhook.
+	Slack NotifierType = "slack"
+	// PagerDuty pages an on-call rotation through the PagerDuty events API.
+	PagerDuty NotifierType = "pagerduty"
+	// OpsGenie creates an OpsGenie alert.
+	OpsGenie NotifierType = "opsgenie"
+	// VictorOps posts to a VictorOps REST endpoint.
+	VictorOps NotifierType = "victorops"
+	// HipChat posts a message to a HipChat room.
+	HipChat NotifierType = "hipchat"
+	// Alerta creates an Alerta alert.
+	Alerta NotifierType = "alerta"
+	// SMTP emails the alert to a list of recipients.
+	SMTP NotifierType = "smtp"
+)
+
+// NotifierSpec is the structured configuration for a single alert handler
+// chained onto a Task. GenerateHandlers writes it into the TICKscript and
+// ParseHandlers reads it back; fields a notifier's Generate does not write
+// (ServiceKey, Token, Group, Tags) are not recovered, and the
+// chronograf.AlertRule returned by Reverse carries no handlers.
+type NotifierSpec struct {
+	Type NotifierType `json:"type"`
+
+	// Slack
+	Channel string `json:"channel,omitempty"`
+
+	// PagerDuty / OpsGenie / VictorOps / Alerta
+	ServiceKey string `json:"serviceKey,omitempty"`
+
+	// HipChat
+	Room  string `json:"room,omitempty"`
+	Token string `json:"token,omitempty"`
+
+	// Alerta
+	Resource string   `json:"resource,omitempty"`
+	Event    string   `json:"event,omitempty"`
+	Group    string   `json:"group,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+
+	// SMTP
+	To []string `json:"to,omitempty"`
+}
+
+// Notifier generates the TICKscript node for a single alert handler and
+// validates that its NotifierSpec carries enough configuration to fire.
+type Notifier interface {
+	Type() NotifierType
+	Validate(spec NotifierSpec) error
+	Generate(spec NotifierSpec) (string, error)
+}
+
+// Notifiers is the set of supported alert handlers, keyed by type. It
+// mirrors the service set Kapacitor itself registers at startup.
+var Notifiers = map[NotifierType]Notifier{
+	Slack:     slackNotifier{},
+	PagerDuty: pagerDutyNotifier{},
+	OpsGenie:  opsGenieNotifier{},
+	VictorOps: victorOpsNotifier{},
+	HipChat:   hipChatNotifier{},
+	Alerta:    alertaNotifier{},
+	SMTP:      smtpNotifier{},
+}
+
+// GenerateHandlers appends a `.<node>()` chain to script for every
+// NotifierSpec in specs, in the order they are configured.
+func GenerateHandlers(specs []NotifierSpec) (string, error) {
+	var b strings.Builder
+	for _, spec := range specs {
+		notifier, ok := Notifiers[spec.Type]
+		if !ok {
+			return "", fmt.Errorf("unknown notifier type %q", spec.Type)
+		}
+		if err := notifier.Validate(spec); err != nil {
+			return "", fmt.Errorf("%s: %v", spec.Type, err)
+		}
+		node, err := notifier.Generate(spec)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(node)
+	}
+	return b.String(), nil
+}
+
+type slackNotifier struct{}
+
+func (slackNotifier) Type() NotifierType { return Slack }
+
+func (slackNotifier) Validate(spec NotifierSpec) error {
+	if spec.Channel == "" {
+		return fmt.Errorf("channel is required")
+	}
+	return nil
+}
+
+func (slackNotifier) Generate(spec NotifierSpec) (string, error) {
+	return fmt.Sprintf("\n    |slack()\n        .channel(%q)", spec.Channel), nil
+}
+
+type pagerDutyNotifier struct{}
+
+func (pagerDutyNotifier) Type() NotifierType { return PagerDuty }
+
+func (pagerDutyNotifier) Validate(spec NotifierSpec) error {
+	if spec.ServiceKey == "" {
+		return fmt.Errorf("serviceKey is required")
+	}
+	return nil
+}
+
+func (pagerDutyNotifier) Generate(spec NotifierSpec) (string, error) {
+	return "\n    |pagerDuty()", nil
+}
+
+type opsGenieNotifier struct{}
+
+func (opsGenieNotifier) Type() NotifierType { return OpsGenie }
+
+func (opsGenieNotifier) Validate(spec NotifierSpec) error {
+	if spec.ServiceKey == "" {
+		return fmt.Errorf("serviceKey is required")
+	}
+	return nil
+}
+
+func (opsGenieNotifier) Generate(spec NotifierSpec) (string, error) {
+	return "\n    |opsGenie()", nil
+}
+
+type victorOpsNotifier struct{}
+
+func (victorOpsNotifier) Type() NotifierType { return VictorOps }
+
+func (victorOpsNotifier) Validate(spec NotifierSpec) error {
+	if spec.ServiceKey == "" {
+		return fmt.Errorf("serviceKey is required")
+	}
+	return nil
+}
+
+func (victorOpsNotifier) Generate(spec NotifierSpec) (string, error) {
+	return "\n    |victorOps()", nil
+}
+
+type hipChatNotifier struct{}
+
+func (hipChatNotifier) Type() NotifierType { return HipChat }
+
+func (hipChatNotifier) Validate(spec NotifierSpec) error {
+	if spec.Room == "" {
+		return fmt.Errorf("room is required")
+	}
+	return nil
+}
+
+func (hipChatNotifier) Generate(spec NotifierSpec) (string, error) {
+	return fmt.Sprintf("\n    |hipChat()\n        .room(%q)", spec.Room), nil
+}
+
+type alertaNotifier struct{}
+
+func (alertaNotifier) Type() NotifierType { return Alerta }
+
+func (alertaNotifier) Validate(spec NotifierSpec) error {
+	if spec.Resource == "" || spec.Event == "" {
+		return fmt.Errorf("resource and event are required")
+	}
+	return nil
+}
+
+func (alertaNotifier) Generate(spec NotifierSpec) (string, error) {
+	return fmt.Sprintf("\n    |alerta()\n        .resource(%q)\n        .event(%q)", spec.Resource, spec.Event), nil
+}
+
+type smtpNotifier struct{}
+
+func (smtpNotifier) Type() NotifierType { return SMTP }
+
+func (smtpNotifier) Validate(spec NotifierSpec) error {
+	if len(spec.To) == 0 {
+		return fmt.Errorf("to is required")
+	}
+	return nil
+}
+
+func (smtpNotifier) Generate(spec NotifierSpec) (string, error) {
+	var b strings.Builder
+	b.WriteString("\n    |email()")
+	for _, to := range spec.To {
+		fmt.Fprintf(&b, "\n        .to(%q)", to)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,69 @@
+package kapacitor
+
+import "regexp"
+
+// handlerBlockPattern matches a single pipeline node name together with
+// every chained property call GenerateHandlers may have appended after it,
+// e.g. `|slack()\n        .channel("#alerts")`, so that ParseHandlers can
+// recover both which notifiers a TICKscript configures and the field values
+// those notifiers were generated with.
+var handlerBlockPattern = regexp.MustCompile(`\|(slack|pagerDuty|opsGenie|victorOps|hipChat|alerta|email)\(\)((?:\n\s+\.\w+\([^)]*\))*)`)
+
+var nodeToType = map[string]NotifierType{
+	"slack":     Slack,
+	"pagerDuty": PagerDuty,
+	"opsGenie":  OpsGenie,
+	"victorOps": VictorOps,
+	"hipChat":   HipChat,
+	"alerta":    Alerta,
+	"email":     SMTP,
+}
+
+// propertyPattern pulls a single chained `.name("value")` call's name and
+// quoted argument out of a handler block.
+var propertyPattern = regexp.MustCompile(`\.(\w+)\("([^"]*)"\)`)
+
+// ParseHandlers scans a TICKscript for notifier nodes and returns the
+// corresponding NotifierSpec values, including the fields GenerateHandlers
+// chained onto each node (e.g. Slack's channel, HipChat's room). Fields a
+// notifier's Generate never writes into the script, such as PagerDuty's
+// ServiceKey, cannot be recovered this way and are left zero-valued; those
+// are configured on the Kapacitor alert service itself rather than per-task.
+func ParseHandlers(script string) []NotifierSpec {
+	matches := handlerBlockPattern.FindAllStringSubmatch(script, -1)
+
+	specs := make([]NotifierSpec, 0, len(matches))
+	for _, m := range matches {
+		t, ok := nodeToType[m[1]]
+		if !ok {
+			continue
+		}
+		specs = append(specs, specFromBlock(t, m[2]))
+	}
+	return specs
+}
+
+// specFromBlock builds a NotifierSpec of type t from the chained property
+// calls in block, e.g. `.channel("#alerts")` or repeated `.to("a@b.com")`
+// calls for SMTP's recipient list.
+func specFromBlock(t NotifierType, block string) NotifierSpec {
+	spec := NotifierSpec{Type: t}
+
+	for _, p := range propertyPattern.FindAllStringSubmatch(block, -1) {
+		name, value := p[1], p[2]
+		switch name {
+		case "channel":
+			spec.Channel = value
+		case "room":
+			spec.Room = value
+		case "resource":
+			spec.Resource = value
+		case "event":
+			spec.Event = value
+		case "to":
+			spec.To = append(spec.To, value)
+		}
+	}
+
+	return spec
+}
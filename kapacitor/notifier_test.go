@@ -0,0 +1,105 @@
+package kapacitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/chronograf"
+)
+
+func TestGenerateHandlersUnknownType(t *testing.T) {
+	_, err := GenerateHandlers([]NotifierSpec{{Type: NotifierType("carrier-pigeon")}})
+	if err == nil {
+		t.Fatal("GenerateHandlers with an unknown notifier type: got nil error, want one")
+	}
+}
+
+func TestGenerateHandlersValidatesSpec(t *testing.T) {
+	_, err := GenerateHandlers([]NotifierSpec{{Type: Slack}})
+	if err == nil {
+		t.Fatal("GenerateHandlers with a Slack spec missing channel: got nil error, want one")
+	}
+}
+
+func TestGenerateHandlersAppendsEveryNode(t *testing.T) {
+	script, err := GenerateHandlers([]NotifierSpec{
+		{Type: Slack, Channel: "#alerts"},
+		{Type: HipChat, Room: "ops"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateHandlers: unexpected error: %v", err)
+	}
+	if !strings.Contains(script, "|slack()") || !strings.Contains(script, "#alerts") {
+		t.Errorf("GenerateHandlers script missing slack node: %s", script)
+	}
+	if !strings.Contains(script, "|hipChat()") || !strings.Contains(script, "ops") {
+		t.Errorf("GenerateHandlers script missing hipChat node: %s", script)
+	}
+}
+
+func TestParseHandlersRecoversConfiguredTypes(t *testing.T) {
+	script, err := GenerateHandlers([]NotifierSpec{
+		{Type: Slack, Channel: "#alerts"},
+		{Type: PagerDuty, ServiceKey: "key"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateHandlers: unexpected error: %v", err)
+	}
+
+	specs := ParseHandlers("stream\n    |from()" + script)
+	if len(specs) != 2 {
+		t.Fatalf("ParseHandlers returned %d specs, want 2: %+v", len(specs), specs)
+	}
+	if specs[0].Type != Slack {
+		t.Errorf("ParseHandlers()[0].Type = %q, want %q", specs[0].Type, Slack)
+	}
+	if specs[1].Type != PagerDuty {
+		t.Errorf("ParseHandlers()[1].Type = %q, want %q", specs[1].Type, PagerDuty)
+	}
+}
+
+func TestParseHandlersRecoversFieldsChainedOntoTheNode(t *testing.T) {
+	script, err := GenerateHandlers([]NotifierSpec{
+		{Type: Slack, Channel: "#alerts"},
+		{Type: HipChat, Room: "ops"},
+		{Type: SMTP, To: []string{"a@example.com", "b@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateHandlers: unexpected error: %v", err)
+	}
+
+	specs := ParseHandlers("stream\n    |from()" + script)
+	if len(specs) != 3 {
+		t.Fatalf("ParseHandlers returned %d specs, want 3: %+v", len(specs), specs)
+	}
+	if specs[0].Channel != "#alerts" {
+		t.Errorf("ParseHandlers()[0].Channel = %q, want %q", specs[0].Channel, "#alerts")
+	}
+	if specs[1].Room != "ops" {
+		t.Errorf("ParseHandlers()[1].Room = %q, want %q", specs[1].Room, "ops")
+	}
+	if len(specs[2].To) != 2 || specs[2].To[0] != "a@example.com" || specs[2].To[1] != "b@example.com" {
+		t.Errorf("ParseHandlers()[2].To = %v, want both recipients recovered in order", specs[2].To)
+	}
+}
+
+func TestAppendHandlersNoop(t *testing.T) {
+	script := chronograf.TICKScript("stream\n    |from()")
+
+	got, err := appendHandlers(script, nil)
+	if err != nil {
+		t.Fatalf("appendHandlers(nil): unexpected error: %v", err)
+	}
+	if got != script {
+		t.Errorf("appendHandlers(nil) = %q, want script unchanged: %q", got, script)
+	}
+}
+
+func TestAppendHandlersPropagatesGenerateError(t *testing.T) {
+	script := chronograf.TICKScript("stream\n    |from()")
+
+	_, err := appendHandlers(script, []NotifierSpec{{Type: Slack}})
+	if err == nil {
+		t.Fatal("appendHandlers with an invalid spec: got nil error, want one")
+	}
+}
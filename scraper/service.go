@@ -0,0 +1,366 @@
+// Package scraper implements a background service that periodically pulls
+// Prometheus text-format metrics from scrape targets and writes the
+// resulting samples into a bucket as line protocol.
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTimeout is used when a target does not specify its own scrape
+	// timeout.
+	DefaultTimeout = 10 * time.Second
+
+	// maxJitter bounds how far a target's first scrape is pushed out so that
+	// a large number of targets configured with the same interval don't all
+	// hit their endpoints at once.
+	maxJitter = 5 * time.Second
+)
+
+// TargetStatus records the outcome of the most recent scrape of a target.
+type TargetStatus struct {
+	TargetID    platform.ID
+	LastScrape  time.Time
+	LastError   string
+	SampleCount int
+}
+
+// Service pulls metrics from scrape targets on a schedule and publishes them
+// as line protocol via Publish.
+type Service struct {
+	Targets platform.ScraperTargetStoreService
+
+	// Publish writes line protocol read from r to the target's organization
+	// and bucket. It is the same entry point the HTTP write path uses.
+	Publish func(orgID, bucketID platform.ID, r io.Reader) error
+
+	Logger *zap.Logger
+
+	client *http.Client
+
+	mu      sync.Mutex
+	baseCtx context.Context
+	cancels map[string]context.CancelFunc
+	status  map[string]*TargetStatus
+	wg      sync.WaitGroup
+}
+
+// NewService constructs a scraper Service. Publish is called once per
+// successful scrape that collected at least one sample, with line protocol
+// for the samples collected.
+func NewService(targets platform.ScraperTargetStoreService, publish func(orgID, bucketID platform.ID, r io.Reader) error, logger *zap.Logger) *Service {
+	return &Service{
+		Targets: targets,
+		Publish: publish,
+		Logger:  logger,
+		client:  &http.Client{},
+		cancels: map[string]context.CancelFunc{},
+		status:  map[string]*TargetStatus{},
+	}
+}
+
+// Open starts a scrape loop for every target configured at the time of the
+// call. Targets added later are not picked up on their own; each starts
+// only when StartTarget is called for it. ctx is retained as the parent of every target's scrape
+// loop, including ones started later via StartTarget, so a single Close
+// stops them all.
+func (s *Service) Open(ctx context.Context) error {
+	s.mu.Lock()
+	s.baseCtx = ctx
+	s.mu.Unlock()
+
+	targets, err := s.Targets.FindScraperTargets(ctx, platform.ScraperTargetFilter{})
+	if err != nil {
+		return fmt.Errorf("scraper: listing targets: %v", err)
+	}
+
+	for _, target := range targets {
+		s.StartTarget(target)
+	}
+
+	return nil
+}
+
+// Close stops every running scrape loop and waits for them to exit.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	for id, cancel := range s.cancels {
+		cancel()
+		delete(s.cancels, id)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// Status returns the last known status for a target.
+func (s *Service) Status(id platform.ID) (TargetStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.status[id.String()]
+	if !ok {
+		return TargetStatus{}, false
+	}
+	return *st, true
+}
+
+// StartTarget launches target's scrape loop against the context passed to
+// Open, so it is cancelled along with every other target on Close. It is
+// safe to call at any time, not just during Open: the HTTP handler calls
+// it after a target is created via POST /api/v2/scrapers so the target
+// starts being scraped immediately rather than at the next process
+// restart. Calling it for a target that is already running replaces the
+// running loop so that changes made via PATCH take effect.
+func (s *Service) StartTarget(target *platform.ScraperTarget) {
+	s.mu.Lock()
+	base := s.baseCtx
+	if base == nil {
+		base = context.Background()
+	}
+	if cancel, ok := s.cancels[target.ID.String()]; ok {
+		cancel()
+	}
+	runCtx, cancel := context.WithCancel(base)
+	s.cancels[target.ID.String()] = cancel
+	s.status[target.ID.String()] = &TargetStatus{TargetID: target.ID}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(runCtx, target)
+	}()
+}
+
+// StopTarget cancels a single target's scrape loop, e.g. after it has been
+// deleted or disabled.
+func (s *Service) StopTarget(id platform.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.cancels[id.String()]; ok {
+		cancel()
+		delete(s.cancels, id.String())
+	}
+	delete(s.status, id.String())
+}
+
+func (s *Service) run(ctx context.Context, target *platform.ScraperTarget) {
+	interval := target.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(maxJitter)))
+	timer := time.NewTimer(jitter)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.scrapeOnce(ctx, target)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) scrapeOnce(ctx context.Context, target *platform.ScraperTarget) {
+	start := time.Now().UTC()
+
+	lp, sampleCount, err := s.scrape(ctx, target, start)
+	if err != nil {
+		s.recordStatus(target.ID, start, err, 0)
+		if s.Logger != nil {
+			s.Logger.Info("scrape failed",
+				zap.String("target", target.ID.String()),
+				zap.String("url", target.URL.String()),
+				zap.Error(err))
+		}
+		return
+	}
+
+	if lp.Len() > 0 {
+		if err := s.Publish(target.OrgID, target.BucketID, lp); err != nil {
+			s.recordStatus(target.ID, start, err, sampleCount)
+			if s.Logger != nil {
+				s.Logger.Info("scrape publish failed",
+					zap.String("target", target.ID.String()),
+					zap.Error(err))
+			}
+			return
+		}
+	}
+
+	s.recordStatus(target.ID, start, nil, sampleCount)
+}
+
+func (s *Service) recordStatus(id platform.ID, scraped time.Time, scrapeErr error, samples int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.status[id.String()]
+	if !ok {
+		st = &TargetStatus{TargetID: id}
+		s.status[id.String()] = st
+	}
+	st.LastScrape = scraped
+	st.SampleCount = samples
+	if scrapeErr != nil {
+		st.LastError = scrapeErr.Error()
+	} else {
+		st.LastError = ""
+	}
+}
+
+// scrape pulls the Prometheus text-format metrics from target's URL and
+// converts the samples into line protocol, aligning every point's
+// timestamp to start so that a single scrape produces a consistent batch.
+func (s *Service) scrape(ctx context.Context, target *platform.ScraperTarget, start time.Time) (*bytes.Buffer, int, error) {
+	req, err := http.NewRequest("GET", target.URL.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req = req.WithContext(ctx)
+
+	if target.Username != "" {
+		req.SetBasicAuth(target.Username, target.Password)
+	}
+
+	client := s.client
+	if target.InsecureSkipVerify {
+		client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("scraping %s: unexpected status %d", target.URL.String(), resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parsing metrics from %s: %v", target.URL.String(), err)
+	}
+
+	var lp bytes.Buffer
+	count := 0
+	for name, family := range families {
+		if denied(target.DeniedLabels, name) {
+			continue
+		}
+		if !allowed(target.AllowedLabels, name) {
+			continue
+		}
+		count += writeFamily(&lp, name, family, start)
+	}
+
+	return &lp, count, nil
+}
+
+func allowed(allowList []string, name string) bool {
+	if len(allowList) == 0 {
+		return true
+	}
+	for _, a := range allowList {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+func denied(denyList []string, name string) bool {
+	for _, d := range denyList {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFamily appends every sample in family to buf as line protocol and
+// returns the number of samples written.
+func writeFamily(buf *bytes.Buffer, name string, family *dto.MetricFamily, ts time.Time) int {
+	count := 0
+	for _, m := range family.Metric {
+		tags := tagsFromLabels(m.GetLabel())
+
+		fmt.Fprintf(buf, "%s", escapeMeasurement(name))
+		for _, t := range tags {
+			fmt.Fprintf(buf, ",%s=%s", t[0], t[1])
+		}
+		fmt.Fprintf(buf, " value=%s %d\n", valueOf(family.GetType(), m), ts.UnixNano())
+		count++
+	}
+	return count
+}
+
+func tagsFromLabels(labels []*dto.LabelPair) [][2]string {
+	tags := make([][2]string, 0, len(labels))
+	for _, l := range labels {
+		tags = append(tags, [2]string{escapeTag(l.GetName()), escapeTag(l.GetValue())})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i][0] < tags[j][0] })
+	return tags
+}
+
+func valueOf(t dto.MetricType, m *dto.Metric) string {
+	switch t {
+	case dto.MetricType_COUNTER:
+		return fmt.Sprintf("%v", m.GetCounter().GetValue())
+	case dto.MetricType_GAUGE:
+		return fmt.Sprintf("%v", m.GetGauge().GetValue())
+	case dto.MetricType_UNTYPED:
+		return fmt.Sprintf("%v", m.GetUntyped().GetValue())
+	default:
+		return "0"
+	}
+}
+
+func escapeMeasurement(s string) string {
+	r := strings.NewReplacer(",", "\\,", " ", "\\ ")
+	return r.Replace(s)
+}
+
+func escapeTag(s string) string {
+	r := strings.NewReplacer(",", "\\,", " ", "\\ ", "=", "\\=")
+	return r.Replace(s)
+}
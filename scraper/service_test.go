@@ -0,0 +1,75 @@
+package scraper
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+var fixedTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestAllowedWithNoAllowList(t *testing.T) {
+	if !allowed(nil, "cpu") {
+		t.Error("allowed(nil, \"cpu\") = false, want true when there is no allow list")
+	}
+}
+
+func TestAllowedRespectsAllowList(t *testing.T) {
+	list := []string{"cpu", "mem"}
+	if !allowed(list, "cpu") {
+		t.Error("allowed(list, \"cpu\") = false, want true")
+	}
+	if allowed(list, "disk") {
+		t.Error("allowed(list, \"disk\") = true, want false")
+	}
+}
+
+func TestDenied(t *testing.T) {
+	list := []string{"secret_metric"}
+	if !denied(list, "secret_metric") {
+		t.Error("denied(list, \"secret_metric\") = false, want true")
+	}
+	if denied(list, "cpu") {
+		t.Error("denied(list, \"cpu\") = true, want false")
+	}
+}
+
+func TestEscapeMeasurement(t *testing.T) {
+	if got, want := escapeMeasurement("cpu usage,total"), `cpu\ usage\,total`; got != want {
+		t.Errorf("escapeMeasurement() = %q, want %q", got, want)
+	}
+}
+
+func TestEscapeTag(t *testing.T) {
+	if got, want := escapeTag("a=b,c d"), `a\=b\,c\ d`; got != want {
+		t.Errorf("escapeTag() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteFamilyGauge(t *testing.T) {
+	value := 42.5
+	family := &dto.MetricFamily{
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{
+			{
+				Label: []*dto.LabelPair{{Name: strPtr("host"), Value: strPtr("a")}},
+				Gauge: &dto.Gauge{Value: &value},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	count := writeFamily(&buf, "temp", family, fixedTime)
+
+	if count != 1 {
+		t.Fatalf("writeFamily returned count %d, want 1", count)
+	}
+	got := buf.String()
+	if !bytes.Contains(buf.Bytes(), []byte("temp,host=a value=42.5")) {
+		t.Errorf("writeFamily produced %q, want it to contain the tagged measurement and value", got)
+	}
+}
+
+func strPtr(s string) *string { return &s }